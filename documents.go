@@ -0,0 +1,113 @@
+package yamltools
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadFileDocuments reads in and parses every YAML document in path,
+// unlike LoadFileFragment which only returns the first.
+func LoadFileDocuments(path string) ([]*yaml.Node, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	docs := make([]*yaml.Node, 0, 1)
+	dec := yaml.NewDecoder(f)
+	for {
+		var fragment Fragment
+		err := dec.Decode(&fragment)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, fragment.Content)
+	}
+	return docs, nil
+}
+
+// WalkAllDocuments applies a TagProcessor to every document in docs, such as
+// those returned by LoadFileDocuments.
+func WalkAllDocuments(docs []*yaml.Node, fn TagProcessor) error {
+	for _, doc := range docs {
+		if err := fn(doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveFileFragment encodes n and writes it to path, overwriting any
+// existing content.
+func SaveFileFragment(path string, n *yaml.Node) error {
+	return encodeToFile(path, n, 2)
+}
+
+// Transform reads path, applies fn to its root node, and writes the result
+// back to path. The file's existing indentation width is detected and
+// preserved; comment placement and quoting style come for free from
+// round-tripping through *yaml.Node.
+func Transform(path string, fn func(*yaml.Node) error) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var f Fragment
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	if err := fn(f.Content); err != nil {
+		return err
+	}
+	return encodeToFile(path, f.Content, detectIndent(data))
+}
+
+// encodeToFile encodes n to a temporary file in the same directory as path
+// and renames it over path, so a failed or partial encode never truncates
+// the caller's existing file.
+func encodeToFile(path string, n *yaml.Node, indent int) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	enc := yaml.NewEncoder(tmp)
+	enc.SetIndent(indent)
+	if err := enc.Encode(n); err != nil {
+		enc.Close()
+		tmp.Close()
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// detectIndent returns the indentation width used by the first indented,
+// non-comment line in data, defaulting to 2 if none is found.
+func detectIndent(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if width := len(line) - len(trimmed); width > 0 {
+			return width
+		}
+	}
+	return 2
+}
@@ -0,0 +1,135 @@
+package yamltools
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestLoadFileDocuments(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "multi.yaml")
+	writeFile(t, path, "a: 1\n---\nb: 2\n---\nc: 3\n")
+
+	docs, err := LoadFileDocuments(path)
+	if err != nil {
+		t.Fatalf("LoadFileDocuments: %v", err)
+	}
+	if len(docs) != 3 {
+		t.Fatalf("got %d documents, want 3", len(docs))
+	}
+	var a struct{ A int }
+	var b struct{ B int }
+	var c struct{ C int }
+	if err := docs[0].Decode(&a); err != nil || a.A != 1 {
+		t.Errorf("doc[0] = %+v (err=%v), want A=1", a, err)
+	}
+	if err := docs[1].Decode(&b); err != nil || b.B != 2 {
+		t.Errorf("doc[1] = %+v (err=%v), want B=2", b, err)
+	}
+	if err := docs[2].Decode(&c); err != nil || c.C != 3 {
+		t.Errorf("doc[2] = %+v (err=%v), want C=3", c, err)
+	}
+}
+
+func TestWalkAllDocuments(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "multi.yaml")
+	writeFile(t, path, "a: !env YAMLTOOLS_TEST_VAR\n---\nb: !env YAMLTOOLS_TEST_VAR\n")
+	t.Setenv("YAMLTOOLS_TEST_VAR", "bar")
+
+	docs, err := LoadFileDocuments(path)
+	if err != nil {
+		t.Fatalf("LoadFileDocuments: %v", err)
+	}
+	if err := WalkAllDocuments(docs, LoadEnvTag); err != nil {
+		t.Fatalf("WalkAllDocuments: %v", err)
+	}
+	var a struct{ A string }
+	var b struct{ B string }
+	docs[0].Decode(&a)
+	docs[1].Decode(&b)
+	if a.A != "bar" || b.B != "bar" {
+		t.Fatalf("a=%q b=%q, want both %q", a.A, b.B, "bar")
+	}
+}
+
+func TestWalkAllDocumentsStopsOnError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "multi.yaml")
+	writeFile(t, path, "a: !env YAMLTOOLS_DOES_NOT_EXIST\n---\nb: 2\n")
+
+	docs, err := LoadFileDocuments(path)
+	if err != nil {
+		t.Fatalf("LoadFileDocuments: %v", err)
+	}
+	if err := WalkAllDocuments(docs, LoadEnvTag); err == nil {
+		t.Fatalf("expected an error from the unset !env variable in the first document")
+	}
+}
+
+func TestTransformPreservesIndentWidth(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "four-space.yaml")
+	writeFile(t, path, "foo:\n    bar: 1\n")
+
+	err := Transform(path, func(n *yaml.Node) error {
+		bar := findKey(t, n, "foo")
+		findKey(t, bar, "bar").Value = "2"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transform: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !strings.Contains(string(data), "    bar: 2\n") {
+		t.Fatalf("output = %q, want bar updated under a 4-space indent", data)
+	}
+}
+
+func TestTransformLeavesFileUntouchedOnError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "foo.yaml")
+	original := "foo: bar\n"
+	writeFile(t, path, original)
+
+	wantErr := errors.New("boom")
+	err := Transform(path, func(n *yaml.Node) error { return wantErr })
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("Transform error = %v, want %v", err, wantErr)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != original {
+		t.Fatalf("file content changed despite fn returning an error: got %q, want %q", data, original)
+	}
+}
+
+func TestSaveFileFragmentLeavesNoTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.yaml")
+
+	root := parseFragment(t, "foo: bar\n")
+	if err := SaveFileFragment(path, root); err != nil {
+		t.Fatalf("SaveFileFragment: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "out.yaml" {
+		t.Fatalf("directory contains %v, want only out.yaml (no leftover temp file)", entries)
+	}
+}
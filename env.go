@@ -0,0 +1,48 @@
+package yamltools
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadEnvTag recursively searches for the !env and !env_default tags from
+// the given node and replaces each with a scalar node holding the resolved
+// environment variable value. !env VAR requires VAR to be set; !env_default
+// [VAR, default] falls back to default when VAR is unset.
+func LoadEnvTag(n *yaml.Node) error {
+	if err := HandleCustomTag(n, "!env", func(n *yaml.Node) error {
+		value, ok := os.LookupEnv(n.Value)
+		if !ok {
+			return fmt.Errorf("!env: environment variable %q is not set", n.Value)
+		}
+		replaceWithString(n, value)
+		return nil
+	}); err != nil {
+		return err
+	}
+	return HandleCustomTag(n, "!env_default", func(n *yaml.Node) error {
+		if n.Kind != yaml.SequenceNode || len(n.Content) != 2 {
+			return fmt.Errorf("!env_default: expected a [VAR, default] sequence")
+		}
+		name, def := n.Content[0].Value, n.Content[1].Value
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			value = def
+		}
+		replaceWithString(n, value)
+		return nil
+	})
+}
+
+// replaceWithString replaces n in place with a string scalar node, keeping
+// n's original style so e.g. a quoted tag value stays quoted.
+func replaceWithString(n *yaml.Node, value string) {
+	*n = yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Tag:   "!!str",
+		Value: value,
+		Style: n.Style,
+	}
+}
@@ -0,0 +1,60 @@
+package yamltools
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestLoadEnvTag(t *testing.T) {
+	t.Setenv("YAMLTOOLS_TEST_VAR", "bar")
+	n := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!env", Value: "YAMLTOOLS_TEST_VAR"}
+	if err := LoadEnvTag(n); err != nil {
+		t.Fatalf("LoadEnvTag: %v", err)
+	}
+	if n.Tag != "!!str" || n.Value != "bar" {
+		t.Fatalf("node = %+v, want a !!str scalar with value %q", n, "bar")
+	}
+}
+
+func TestLoadEnvTagUnsetErrors(t *testing.T) {
+	n := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!env", Value: "YAMLTOOLS_DOES_NOT_EXIST"}
+	if err := LoadEnvTag(n); err == nil {
+		t.Fatalf("expected an error for an unset environment variable")
+	}
+}
+
+func TestLoadEnvDefaultTagFallsBackWhenUnset(t *testing.T) {
+	n := &yaml.Node{
+		Kind: yaml.SequenceNode,
+		Tag:  "!env_default",
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "YAMLTOOLS_DOES_NOT_EXIST"},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "fallback"},
+		},
+	}
+	if err := LoadEnvTag(n); err != nil {
+		t.Fatalf("LoadEnvTag: %v", err)
+	}
+	if n.Value != "fallback" {
+		t.Fatalf("value = %q, want %q", n.Value, "fallback")
+	}
+}
+
+func TestLoadEnvDefaultTagUsesSetValue(t *testing.T) {
+	t.Setenv("YAMLTOOLS_TEST_VAR", "bar")
+	n := &yaml.Node{
+		Kind: yaml.SequenceNode,
+		Tag:  "!env_default",
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "YAMLTOOLS_TEST_VAR"},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "fallback"},
+		},
+	}
+	if err := LoadEnvTag(n); err != nil {
+		t.Fatalf("LoadEnvTag: %v", err)
+	}
+	if n.Value != "bar" {
+		t.Fatalf("value = %q, want %q", n.Value, "bar")
+	}
+}
@@ -0,0 +1,232 @@
+package yamltools
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/fs"
+	"path/filepath"
+	"sort"
+)
+
+// dirEntry describes a single file discovered while walking a directory for
+// one of the !include_dir_* tags.
+type dirEntry struct {
+	// path is the full filesystem path to the file.
+	path string
+	// key is the name to expose the file under, with its extension
+	// stripped. For a non-recursive walk this is just the file's base name,
+	// for a recursive walk it is the path relative to the walked directory.
+	key string
+}
+
+// listDir walks dir and returns every file found, sorted by path. When
+// recursive is false, sub-directories are skipped entirely rather than
+// walked into.
+func listDir(dir string, recursive bool) ([]dirEntry, error) {
+	entries := make([]dirEntry, 0, 10)
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		if entry.IsDir() {
+			if !recursive {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		key := fileNameWithoutExt(filepath.Base(path))
+		if recursive {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			key = filepath.ToSlash(fileNameWithoutExt(rel))
+		}
+		entries = append(entries, dirEntry{path: path, key: key})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].path < entries[j].path })
+	return entries, nil
+}
+
+// LoadIncludeDirNamedTag recursively searches for the !include_dir_named tag from the given node
+// and will replace the tag node with map of filename to content for each file in the directory.
+func LoadIncludeDirNamedTag(n *yaml.Node) error {
+	return loadIncludeDirNamed(n, "!include_dir_named", false)
+}
+
+// LoadIncludeDirNamedRecursiveTag is the recursive counterpart of LoadIncludeDirNamedTag: it
+// descends into sub-directories, keying each file by its path relative to the included
+// directory (minus extension) instead of just its base name.
+func LoadIncludeDirNamedRecursiveTag(n *yaml.Node) error {
+	return loadIncludeDirNamed(n, "!include_dir_named_recursive", true)
+}
+
+func loadIncludeDirNamed(n *yaml.Node, tag string, recursive bool) error {
+	return HandleCustomTag(n, tag, func(n *yaml.Node) error {
+		entries, err := listDir(n.Value, recursive)
+		if err != nil {
+			return err
+		}
+		content := make([]*yaml.Node, 0, len(entries)*2)
+		for _, entry := range entries {
+			fragment, err := LoadFileFragment(entry.path)
+			if err != nil {
+				return err
+			}
+			content = append(content, &yaml.Node{
+				Kind:  yaml.ScalarNode,
+				Tag:   "!!str",
+				Value: entry.key,
+			}, fragment)
+		}
+		*n = yaml.Node{
+			Kind:    yaml.MappingNode,
+			Tag:     "!!map",
+			Content: content,
+		}
+		return nil
+	})
+}
+
+// LoadIncludeDirListTag recursively searches for the !include_dir_list tag from the given node
+// and will replace the tag node with the concatenation of each file's top-level content, which
+// must be a sequence, into a single sequence.
+func LoadIncludeDirListTag(n *yaml.Node) error {
+	return loadIncludeDirList(n, "!include_dir_list", false)
+}
+
+// LoadIncludeDirListRecursiveTag is the recursive counterpart of LoadIncludeDirListTag.
+func LoadIncludeDirListRecursiveTag(n *yaml.Node) error {
+	return loadIncludeDirList(n, "!include_dir_list_recursive", true)
+}
+
+func loadIncludeDirList(n *yaml.Node, tag string, recursive bool) error {
+	return HandleCustomTag(n, tag, func(n *yaml.Node) error {
+		entries, err := listDir(n.Value, recursive)
+		if err != nil {
+			return err
+		}
+		content := make([]*yaml.Node, 0, len(entries))
+		for _, entry := range entries {
+			fragment, err := LoadFileFragment(entry.path)
+			if err != nil {
+				return err
+			}
+			if fragment.Kind != yaml.SequenceNode {
+				return fmt.Errorf("%s: %s does not contain a sequence", tag, entry.path)
+			}
+			content = append(content, fragment.Content...)
+		}
+		*n = yaml.Node{
+			Kind:    yaml.SequenceNode,
+			Tag:     "!!seq",
+			Content: content,
+		}
+		return nil
+	})
+}
+
+// LoadIncludeDirMergeNamedTag recursively searches for the !include_dir_merge_named tag from the
+// given node and will replace the tag node with a single mapping formed by merging together the
+// top-level mapping of every file in the directory.
+func LoadIncludeDirMergeNamedTag(n *yaml.Node) error {
+	return loadIncludeDirMergeNamed(n, "!include_dir_merge_named", false)
+}
+
+// LoadIncludeDirMergeNamedRecursiveTag is the recursive counterpart of
+// LoadIncludeDirMergeNamedTag.
+func LoadIncludeDirMergeNamedRecursiveTag(n *yaml.Node) error {
+	return loadIncludeDirMergeNamed(n, "!include_dir_merge_named_recursive", true)
+}
+
+func loadIncludeDirMergeNamed(n *yaml.Node, tag string, recursive bool) error {
+	return HandleCustomTag(n, tag, func(n *yaml.Node) error {
+		entries, err := listDir(n.Value, recursive)
+		if err != nil {
+			return err
+		}
+		content := make([]*yaml.Node, 0, len(entries)*2)
+		for _, entry := range entries {
+			fragment, err := LoadFileFragment(entry.path)
+			if err != nil {
+				return err
+			}
+			if fragment.Kind != yaml.MappingNode {
+				return fmt.Errorf("%s: %s does not contain a mapping", tag, entry.path)
+			}
+			for i := 0; i+1 < len(fragment.Content); i += 2 {
+				key, value := fragment.Content[i], fragment.Content[i+1]
+				if j := mappingKeyIndex(content, key.Value); j != -1 {
+					content[j+1] = value
+				} else {
+					content = append(content, key, value)
+				}
+			}
+		}
+		*n = yaml.Node{
+			Kind:    yaml.MappingNode,
+			Tag:     "!!map",
+			Content: content,
+		}
+		return nil
+	})
+}
+
+// mappingKeyIndex returns the index of key within a mapping node's Content
+// slice, or -1 if it is not present.
+func mappingKeyIndex(content []*yaml.Node, key string) int {
+	for i := 0; i+1 < len(content); i += 2 {
+		if content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// LoadIncludeDirMergeListTag recursively searches for the !include_dir_merge_list tag from the
+// given node and will replace the tag node with a single sequence formed by appending the
+// top-level content of every file in the directory, flattening any file that is itself a
+// sequence and appending scalars and mappings as single items.
+func LoadIncludeDirMergeListTag(n *yaml.Node) error {
+	return loadIncludeDirMergeList(n, "!include_dir_merge_list", false)
+}
+
+// LoadIncludeDirMergeListRecursiveTag is the recursive counterpart of
+// LoadIncludeDirMergeListTag.
+func LoadIncludeDirMergeListRecursiveTag(n *yaml.Node) error {
+	return loadIncludeDirMergeList(n, "!include_dir_merge_list_recursive", true)
+}
+
+func loadIncludeDirMergeList(n *yaml.Node, tag string, recursive bool) error {
+	return HandleCustomTag(n, tag, func(n *yaml.Node) error {
+		entries, err := listDir(n.Value, recursive)
+		if err != nil {
+			return err
+		}
+		content := make([]*yaml.Node, 0, len(entries))
+		for _, entry := range entries {
+			fragment, err := LoadFileFragment(entry.path)
+			if err != nil {
+				return err
+			}
+			if fragment.Kind == yaml.SequenceNode {
+				content = append(content, fragment.Content...)
+			} else {
+				content = append(content, fragment)
+			}
+		}
+		*n = yaml.Node{
+			Kind:    yaml.SequenceNode,
+			Tag:     "!!seq",
+			Content: content,
+		}
+		return nil
+	})
+}
@@ -0,0 +1,145 @@
+package yamltools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+func TestLoadIncludeDirNamedTag(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.yaml"), "x: 1\n")
+	writeFile(t, filepath.Join(dir, "b.yaml"), "y: 2\n")
+	writeFile(t, filepath.Join(dir, "sub", "c.yaml"), "z: 3\n")
+
+	n := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!include_dir_named", Value: dir}
+	if err := LoadIncludeDirNamedTag(n); err != nil {
+		t.Fatalf("LoadIncludeDirNamedTag: %v", err)
+	}
+	var out map[string]map[string]int
+	if err := n.Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := map[string]map[string]int{"a": {"x": 1}, "b": {"y": 2}}
+	if len(out) != len(want) || out["a"]["x"] != 1 || out["b"]["y"] != 2 {
+		t.Fatalf("non-recursive walk got %v, want %v (sub-directory should be skipped)", out, want)
+	}
+}
+
+func TestLoadIncludeDirNamedRecursiveTag(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.yaml"), "x: 1\n")
+	writeFile(t, filepath.Join(dir, "sub", "c.yaml"), "z: 3\n")
+
+	n := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!include_dir_named_recursive", Value: dir}
+	if err := LoadIncludeDirNamedRecursiveTag(n); err != nil {
+		t.Fatalf("LoadIncludeDirNamedRecursiveTag: %v", err)
+	}
+	var out map[string]map[string]int
+	if err := n.Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if out["a"]["x"] != 1 || out["sub/c"]["z"] != 3 {
+		t.Fatalf("recursive walk got %v, want keys \"a\" and \"sub/c\"", out)
+	}
+}
+
+func TestLoadIncludeDirListTag(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "list1.yaml"), "- 1\n- 2\n")
+	writeFile(t, filepath.Join(dir, "list2.yaml"), "- 3\n")
+
+	n := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!include_dir_list", Value: dir}
+	if err := LoadIncludeDirListTag(n); err != nil {
+		t.Fatalf("LoadIncludeDirListTag: %v", err)
+	}
+	var out []int
+	if err := n.Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if want := []int{1, 2, 3}; !intSlicesEqual(out, want) {
+		t.Fatalf("LoadIncludeDirListTag = %v, want %v", out, want)
+	}
+}
+
+func TestLoadIncludeDirListTagRejectsNonSequence(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "not-a-list.yaml"), "x: 1\n")
+
+	n := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!include_dir_list", Value: dir}
+	if err := LoadIncludeDirListTag(n); err == nil {
+		t.Fatalf("expected an error for a file whose content is not a sequence")
+	}
+}
+
+func TestLoadIncludeDirMergeNamedTag(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "1-base.yaml"), "shared: base\nonly1: a\n")
+	writeFile(t, filepath.Join(dir, "2-override.yaml"), "shared: override\nonly2: b\n")
+
+	n := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!include_dir_merge_named", Value: dir}
+	if err := LoadIncludeDirMergeNamedTag(n); err != nil {
+		t.Fatalf("LoadIncludeDirMergeNamedTag: %v", err)
+	}
+	var out map[string]string
+	if err := n.Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := map[string]string{"shared": "override", "only1": "a", "only2": "b"}
+	for k, v := range want {
+		if out[k] != v {
+			t.Errorf("out[%q] = %q, want %q", k, out[k], v)
+		}
+	}
+	if len(out) != len(want) {
+		t.Fatalf("out has duplicate or extra keys: %v", out)
+	}
+}
+
+func TestLoadIncludeDirMergeListTag(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "1-seq.yaml"), "- 1\n- 2\n")
+	writeFile(t, filepath.Join(dir, "2-scalar.yaml"), "solo\n")
+	writeFile(t, filepath.Join(dir, "3-map.yaml"), "k: v\n")
+
+	n := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!include_dir_merge_list", Value: dir}
+	if err := LoadIncludeDirMergeListTag(n); err != nil {
+		t.Fatalf("LoadIncludeDirMergeListTag: %v", err)
+	}
+	if len(n.Content) != 4 {
+		t.Fatalf("LoadIncludeDirMergeListTag produced %d items, want 4: %v", len(n.Content), n.Content)
+	}
+	if n.Content[0].Value != "1" || n.Content[1].Value != "2" {
+		t.Errorf("sequence file was not flattened: %v, %v", n.Content[0].Value, n.Content[1].Value)
+	}
+	if n.Content[2].Value != "solo" {
+		t.Errorf("scalar file should append as one item, got %v", n.Content[2])
+	}
+	if n.Content[3].Kind != yaml.MappingNode {
+		t.Errorf("mapping file should append as one item, got kind=%v", n.Content[3].Kind)
+	}
+}
+
+func intSlicesEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+package yamltools
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadIncludeGlobTag returns a TagProcessor that recursively searches for the
+// !include_glob tag, whose value is a doublestar glob pattern (e.g.
+// "conf.d/**/*.yaml") resolved relative to baseDir, and replaces it with a
+// sequence of the matched files loaded in sorted order.
+func LoadIncludeGlobTag(baseDir string) TagProcessor {
+	return func(n *yaml.Node) error {
+		return HandleCustomTag(n, "!include_glob", func(n *yaml.Node) error {
+			pattern := n.Value
+			if baseDir != "" && !filepath.IsAbs(pattern) {
+				pattern = filepath.Join(baseDir, pattern)
+			}
+			matches, err := doublestar.FilepathGlob(pattern)
+			if err != nil {
+				return err
+			}
+			sort.Strings(matches)
+			content := make([]*yaml.Node, 0, len(matches))
+			for _, match := range matches {
+				fragment, err := LoadFileFragment(match)
+				if err != nil {
+					return err
+				}
+				content = append(content, fragment)
+			}
+			*n = yaml.Node{
+				Kind:    yaml.SequenceNode,
+				Tag:     "!!seq",
+				Content: content,
+			}
+			return nil
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package yamltools
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestLoadIncludeGlobTag(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.yaml"), "x: 1\n")
+	writeFile(t, filepath.Join(dir, "b.yaml"), "x: 2\n")
+	writeFile(t, filepath.Join(dir, "sub", "c.yaml"), "x: 3\n")
+
+	n := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!include_glob", Value: "**/*.yaml"}
+	if err := LoadIncludeGlobTag(dir)(n); err != nil {
+		t.Fatalf("LoadIncludeGlobTag: %v", err)
+	}
+
+	var out []struct{ X int }
+	if err := n.Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if len(out) != 3 {
+		t.Fatalf("got %d matches, want 3: %v", len(out), out)
+	}
+	if out[0].X != 1 || out[1].X != 2 || out[2].X != 3 {
+		t.Fatalf("matches not in sorted-path order: %v", out)
+	}
+}
+
+func TestLoadIncludeGlobTagNoMatches(t *testing.T) {
+	dir := t.TempDir()
+
+	n := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!include_glob", Value: "*.yaml"}
+	if err := LoadIncludeGlobTag(dir)(n); err != nil {
+		t.Fatalf("LoadIncludeGlobTag: %v", err)
+	}
+	if n.Kind != yaml.SequenceNode || len(n.Content) != 0 {
+		t.Fatalf("expected an empty sequence, got %+v", n)
+	}
+}
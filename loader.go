@@ -0,0 +1,89 @@
+package yamltools
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultMaxIncludeDepth bounds how many nested !include files Loader.Load
+// will follow before giving up, guarding against a file that !includes
+// itself.
+const defaultMaxIncludeDepth = 100
+
+// Loader bundles a set of enabled tag processors with a base directory for
+// resolving relative !include paths and a maximum include depth.
+//
+// LoadFileFragment and LoadIncludeTag resolve !include values as raw paths,
+// which breaks nested includes once an included file itself !includes a
+// path relative to its own directory rather than the caller's. Loader fixes
+// this by tracking, for every nested !include, the directory of the file
+// that contains it.
+type Loader struct {
+	// Processors is applied, in order, to the fully include-resolved node.
+	Processors []TagProcessor
+	// BaseDir anchors the path passed to Load. Relative !include paths
+	// inside the loaded files are resolved relative to their own file,
+	// not BaseDir.
+	BaseDir string
+	// MaxDepth is the maximum !include nesting depth. Zero means
+	// defaultMaxIncludeDepth.
+	MaxDepth int
+}
+
+// NewLoader constructs a Loader with the given processors and the default
+// max include depth.
+func NewLoader(processors ...TagProcessor) *Loader {
+	return &Loader{Processors: processors}
+}
+
+// Load reads path (resolved relative to l.BaseDir), recursively resolves
+// !include tags relative to each including file's own directory, then
+// applies every processor in l.Processors to the result.
+func (l *Loader) Load(path string) (*yaml.Node, error) {
+	if l.BaseDir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(l.BaseDir, path)
+	}
+	n, err := l.loadInclude(path, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, process := range l.Processors {
+		if err := process(n); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+func (l *Loader) loadInclude(path string, depth int) (*yaml.Node, error) {
+	maxDepth := l.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = defaultMaxIncludeDepth
+	}
+	if depth > maxDepth {
+		return nil, fmt.Errorf("yamltools: exceeded max include depth (%d) loading %s", maxDepth, path)
+	}
+	n, err := LoadFileFragment(path)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(path)
+	err = HandleCustomTag(n, "!include", func(n *yaml.Node) error {
+		includePath := n.Value
+		if !filepath.IsAbs(includePath) {
+			includePath = filepath.Join(dir, includePath)
+		}
+		fragment, err := l.loadInclude(includePath, depth+1)
+		if err != nil {
+			return err
+		}
+		*n = *fragment
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
@@ -0,0 +1,65 @@
+package yamltools
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoaderResolvesNestedIncludesRelativeToOwnDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "root.yaml"), "a: !include sub/child.yaml\n")
+	writeFile(t, filepath.Join(dir, "sub", "child.yaml"), "b: !include grandchild.yaml\n")
+	writeFile(t, filepath.Join(dir, "sub", "grandchild.yaml"), "c: 1\n")
+
+	l := &Loader{BaseDir: dir}
+	n, err := l.Load("root.yaml")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	var out struct {
+		A struct {
+			B struct{ C int }
+		}
+	}
+	if err := n.Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if out.A.B.C != 1 {
+		t.Fatalf("a.b.c = %d, want 1 (grandchild.yaml should resolve relative to sub/, not the base dir)", out.A.B.C)
+	}
+}
+
+func TestLoaderMaxDepthGuard(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "self.yaml"), "a: !include self.yaml\n")
+
+	l := &Loader{BaseDir: dir, MaxDepth: 3}
+	_, err := l.Load("self.yaml")
+	if err == nil {
+		t.Fatalf("expected an error for a file that !includes itself")
+	}
+	if !strings.Contains(err.Error(), "max include depth") {
+		t.Fatalf("error = %q, want it to mention the max include depth", err)
+	}
+}
+
+func TestLoaderAppliesProcessors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "root.yaml"), "x: !env YAMLTOOLS_TEST_VAR\n")
+	t.Setenv("YAMLTOOLS_TEST_VAR", "bar")
+
+	l := &Loader{BaseDir: dir, Processors: []TagProcessor{LoadEnvTag}}
+	n, err := l.Load("root.yaml")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	var out struct{ X string }
+	if err := n.Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if out.X != "bar" {
+		t.Fatalf("x = %q, want %q", out.X, "bar")
+	}
+}
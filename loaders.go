@@ -2,9 +2,7 @@ package yamltools
 
 import (
 	"gopkg.in/yaml.v3"
-	"io/fs"
 	"os"
-	"path/filepath"
 )
 
 type TagProcessor = func(n *yaml.Node) error
@@ -75,41 +73,6 @@ func LoadIncludeTag(n *yaml.Node) error {
 	})
 }
 
-// LoadIncludeDirNamedTag recursively searches for the !include_dir_named tag from the given node
-// and will replace the tag node with map of filename to content for each file in the directory.
-func LoadIncludeDirNamedTag(n *yaml.Node) error {
-	return HandleCustomTag(n, "!include_dir_named", func(n *yaml.Node) error {
-		content := make([]*yaml.Node, 0, 10)
-		err := filepath.WalkDir(n.Value, func(path string, entry fs.DirEntry, err error) error {
-			if path == n.Value {
-				return nil
-			}
-			if entry.IsDir() {
-				return nil
-			}
-			fragment, err := LoadFileFragment(path)
-			if err != nil {
-				return err
-			}
-			content = append(content, &yaml.Node{
-				Kind:  yaml.ScalarNode,
-				Tag:   "!!str",
-				Value: fileNameWithoutExt(filepath.Base(path)),
-			}, fragment)
-			return nil
-		})
-		if err != nil {
-			return err
-		}
-		*n = *&yaml.Node{
-			Kind:    yaml.MappingNode,
-			Tag:     "!!map",
-			Content: content,
-		}
-		return nil
-	})
-}
-
 func fileNameWithoutExt(path string) string {
 	for i := len(path) - 1; i >= 0 && !os.IsPathSeparator(path[i]); i-- {
 		if path[i] == '.' {
@@ -0,0 +1,129 @@
+// Package overlay deep-merges a logical config path across an ordered list of
+// filesystem roots, the way layered defaults/site/host YAML files are
+// assembled by tools such as Kustomize overlays.
+package overlay
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/jcwillox/go-yamltools"
+	"gopkg.in/yaml.v3"
+)
+
+// MergeStrategy controls how a sequence node is combined with the same
+// sequence from a higher-priority root.
+type MergeStrategy int
+
+const (
+	// MergeStrategyReplace discards the lower-priority sequence entirely in
+	// favour of the higher-priority one. This is the default strategy.
+	MergeStrategyReplace MergeStrategy = iota
+	// MergeStrategyAppend concatenates the higher-priority sequence onto
+	// the end of the lower-priority one.
+	MergeStrategyAppend
+)
+
+// Overlay assembles a logical config path out of an ordered list of roots,
+// deep-merging the YAML node from each root that provides the path. Roots
+// are ordered from lowest to highest priority, so later roots win.
+type Overlay struct {
+	// Roots is the ordered list of filesystem roots to search, from
+	// lowest to highest priority.
+	Roots []fs.FS
+	// Strategy picks the MergeStrategy to use for the sequence found at
+	// path, where path is the sequence of mapping keys leading to it. If
+	// nil, MergeStrategyReplace is used for every sequence.
+	Strategy func(path []string) MergeStrategy
+}
+
+// Assemble loads logicalPath from every root that provides it and deep-merges
+// the resulting nodes into one, preserving the comments, styles, and tags of
+// the highest-priority source. It returns an error if no root provides
+// logicalPath.
+func (o *Overlay) Assemble(logicalPath string) (*yaml.Node, error) {
+	var merged *yaml.Node
+	found := false
+	for _, root := range o.Roots {
+		data, err := fs.ReadFile(root, logicalPath)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		var f yamltools.Fragment
+		if err := yaml.Unmarshal(data, &f); err != nil {
+			return nil, err
+		}
+		found = true
+		merged = o.merge(merged, f.Content, nil)
+	}
+	if !found {
+		return nil, fmt.Errorf("overlay: %s not found in any root", logicalPath)
+	}
+	return merged, nil
+}
+
+// merge deep-merges src, the higher-priority node, into dst, returning the
+// merged node. dst may be nil, in which case src is returned as-is.
+func (o *Overlay) merge(dst, src *yaml.Node, path []string) *yaml.Node {
+	if dst == nil {
+		return src
+	}
+	if dst.Kind == yaml.MappingNode && src.Kind == yaml.MappingNode {
+		return o.mergeMappings(dst, src, path)
+	}
+	if dst.Kind == yaml.SequenceNode && src.Kind == yaml.SequenceNode {
+		if o.strategy(path) == MergeStrategyAppend {
+			merged := *src
+			merged.Content = append(append([]*yaml.Node{}, dst.Content...), src.Content...)
+			return &merged
+		}
+		return src
+	}
+	// scalars, or a kind mismatch between layers: the higher-priority
+	// source always overrides.
+	return src
+}
+
+// mergeMappings merges the keys of dst into src key-wise, recursing into any
+// key present in both. The result is seeded from src, the higher-priority
+// node, so its Style/Tag and any comments are preserved; only keys dst has
+// that src doesn't are carried over from dst.
+func (o *Overlay) mergeMappings(dst, src *yaml.Node, path []string) *yaml.Node {
+	merged := *src
+	merged.Content = append([]*yaml.Node{}, src.Content...)
+	for i := 0; i+1 < len(merged.Content); i += 2 {
+		key, value := merged.Content[i], merged.Content[i+1]
+		if j := mappingKeyIndex(dst.Content, key.Value); j != -1 {
+			merged.Content[i+1] = o.merge(dst.Content[j+1], value, append(path, key.Value))
+		}
+	}
+	for i := 0; i+1 < len(dst.Content); i += 2 {
+		key, value := dst.Content[i], dst.Content[i+1]
+		if mappingKeyIndex(merged.Content, key.Value) == -1 {
+			merged.Content = append(merged.Content, key, value)
+		}
+	}
+	return &merged
+}
+
+// mappingKeyIndex returns the index of key within a mapping node's Content
+// slice, or -1 if it is not present.
+func mappingKeyIndex(content []*yaml.Node, key string) int {
+	for i := 0; i+1 < len(content); i += 2 {
+		if content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func (o *Overlay) strategy(path []string) MergeStrategy {
+	if o.Strategy == nil {
+		return MergeStrategyReplace
+	}
+	return o.Strategy(path)
+}
@@ -0,0 +1,152 @@
+package overlay
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"gopkg.in/yaml.v3"
+)
+
+func mapFS(content string) fstest.MapFS {
+	return fstest.MapFS{"app.yaml": &fstest.MapFile{Data: []byte(content)}}
+}
+
+func TestAssembleMergesMappings(t *testing.T) {
+	base := mapFS("foo:\n  a: 1\n  b: 2\n")
+	override := mapFS("foo:\n  b: 99\n  c: 3\n")
+
+	o := &Overlay{Roots: []fs.FS{base, override}}
+	n, err := o.Assemble("app.yaml")
+	if err != nil {
+		t.Fatalf("Assemble: %v", err)
+	}
+
+	var out map[string]map[string]int
+	if err := n.Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := map[string]int{"a": 1, "b": 99, "c": 3}
+	for k, v := range want {
+		if out["foo"][k] != v {
+			t.Errorf("foo[%q] = %v, want %v", k, out["foo"][k], v)
+		}
+	}
+	if len(out["foo"]) != len(want) {
+		t.Fatalf("foo = %v, want %v", out["foo"], want)
+	}
+}
+
+func TestAssembleSequenceDefaultReplace(t *testing.T) {
+	base := mapFS("list:\n  - 1\n  - 2\n")
+	override := mapFS("list:\n  - 3\n")
+
+	o := &Overlay{Roots: []fs.FS{base, override}}
+	n, err := o.Assemble("app.yaml")
+	if err != nil {
+		t.Fatalf("Assemble: %v", err)
+	}
+
+	var out struct{ List []int }
+	if err := n.Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if want := []int{3}; !intSliceEqual(out.List, want) {
+		t.Fatalf("List = %v, want %v (default strategy should replace)", out.List, want)
+	}
+}
+
+func TestAssembleSequenceAppendStrategy(t *testing.T) {
+	base := mapFS("list:\n  - 1\n  - 2\n")
+	override := mapFS("list:\n  - 3\n")
+
+	o := &Overlay{
+		Roots: []fs.FS{base, override},
+		Strategy: func(path []string) MergeStrategy {
+			if len(path) == 1 && path[0] == "list" {
+				return MergeStrategyAppend
+			}
+			return MergeStrategyReplace
+		},
+	}
+	n, err := o.Assemble("app.yaml")
+	if err != nil {
+		t.Fatalf("Assemble: %v", err)
+	}
+
+	var out struct{ List []int }
+	if err := n.Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if want := []int{1, 2, 3}; !intSliceEqual(out.List, want) {
+		t.Fatalf("List = %v, want %v", out.List, want)
+	}
+}
+
+func TestAssemblePreservesHighestPrioritySourceStyle(t *testing.T) {
+	base := mapFS("foo:\n  a: 1\n")
+	override := mapFS("foo: {a: 99}\n")
+
+	o := &Overlay{Roots: []fs.FS{base, override}}
+	n, err := o.Assemble("app.yaml")
+	if err != nil {
+		t.Fatalf("Assemble: %v", err)
+	}
+
+	foo := mappingValue(n, "foo")
+	if foo == nil {
+		t.Fatalf("foo key not found in merged result")
+	}
+	if foo.Style != yaml.FlowStyle {
+		t.Fatalf("foo.Style = %v, want FlowStyle (the higher-priority source's style)", foo.Style)
+	}
+}
+
+func TestAssembleSkipsMissingFileInLowerRoot(t *testing.T) {
+	empty := fstest.MapFS{}
+	override := mapFS("foo: 1\n")
+
+	o := &Overlay{Roots: []fs.FS{empty, override}}
+	n, err := o.Assemble("app.yaml")
+	if err != nil {
+		t.Fatalf("Assemble: %v", err)
+	}
+	var out map[string]int
+	if err := n.Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if out["foo"] != 1 {
+		t.Fatalf("foo = %v, want 1", out["foo"])
+	}
+}
+
+func TestAssembleErrorsWhenNoRootProvidesPath(t *testing.T) {
+	o := &Overlay{Roots: []fs.FS{fstest.MapFS{}, fstest.MapFS{}}}
+	if _, err := o.Assemble("missing.yaml"); err == nil {
+		t.Fatalf("expected an error when no root provides the logical path")
+	}
+}
+
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func intSliceEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+// Package path implements a Spruce/ytbx-style path syntax for querying and
+// mutating a *yaml.Node tree: "/foo/bar/0" indexes a mapping then a
+// sequence, "/list/name=frontend/image" finds the sequence element whose
+// "name" key is "frontend", and "**/image" recurses into every descendant
+// looking for an "image" key.
+package path
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Get resolves path against root and returns every matching node. The
+// returned nodes are pointers into root itself, not copies, so callers may
+// mutate their Style, Tag, or Content in place.
+func Get(root *yaml.Node, path string) ([]*yaml.Node, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return match(root, segments), nil
+}
+
+// Set resolves path against root and overwrites every matching node with
+// value. It returns an error if path matches no node.
+func Set(root *yaml.Node, path string, value *yaml.Node) error {
+	matches, err := Get(root, path)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("path: %q matched no nodes", path)
+	}
+	// Each match gets its own copy of value: Get's contract promises every
+	// match is an independently mutable node, so sharing value's Content
+	// slice across matches would let mutating one match corrupt the rest.
+	for _, n := range matches {
+		*n = *cloneNode(value)
+	}
+	return nil
+}
+
+// cloneNode deep-copies n, including its Content slice and every
+// descendant node.
+func cloneNode(n *yaml.Node) *yaml.Node {
+	if n == nil {
+		return nil
+	}
+	clone := *n
+	if n.Content != nil {
+		clone.Content = make([]*yaml.Node, len(n.Content))
+		for i, c := range n.Content {
+			clone.Content[i] = cloneNode(c)
+		}
+	}
+	return &clone
+}
+
+func parsePath(path string) ([]string, error) {
+	path = strings.TrimPrefix(path, "/")
+	if path == "" {
+		return nil, nil
+	}
+	segments := strings.Split(path, "/")
+	for _, s := range segments {
+		if s == "" {
+			return nil, fmt.Errorf("path: %q contains an empty segment", path)
+		}
+	}
+	return segments, nil
+}
+
+func match(n *yaml.Node, segments []string) []*yaml.Node {
+	if len(segments) == 0 {
+		return []*yaml.Node{n}
+	}
+
+	segment, rest := segments[0], segments[1:]
+
+	if segment == "**" {
+		matches := match(n, rest)
+		for _, child := range children(n) {
+			matches = append(matches, match(child, segments)...)
+		}
+		return matches
+	}
+
+	if key, value, ok := strings.Cut(segment, "="); ok {
+		if n.Kind != yaml.SequenceNode {
+			return nil
+		}
+		var matches []*yaml.Node
+		for _, item := range n.Content {
+			if field := mappingValue(item, key); field != nil && field.Value == value {
+				matches = append(matches, match(item, rest)...)
+			}
+		}
+		return matches
+	}
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		if v := mappingValue(n, segment); v != nil {
+			return match(v, rest)
+		}
+	case yaml.SequenceNode:
+		if idx, err := strconv.Atoi(segment); err == nil && idx >= 0 && idx < len(n.Content) {
+			return match(n.Content[idx], rest)
+		}
+	}
+	return nil
+}
+
+// children returns the direct descendant value nodes of n, used to expand
+// the "**" recursive-descent segment.
+func children(n *yaml.Node) []*yaml.Node {
+	switch n.Kind {
+	case yaml.SequenceNode:
+		return n.Content
+	case yaml.MappingNode:
+		values := make([]*yaml.Node, 0, len(n.Content)/2)
+		for i := 1; i < len(n.Content); i += 2 {
+			values = append(values, n.Content[i])
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// mappingValue returns the value node for key in mapping n, or nil if n is
+// not a mapping or does not contain key.
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
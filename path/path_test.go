@@ -0,0 +1,141 @@
+package path
+
+import (
+	"testing"
+
+	"github.com/jcwillox/go-yamltools"
+	"gopkg.in/yaml.v3"
+)
+
+func parseFragment(t *testing.T, data string) *yaml.Node {
+	t.Helper()
+	var f yamltools.Fragment
+	if err := yaml.Unmarshal([]byte(data), &f); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return f.Content
+}
+
+func TestGet(t *testing.T) {
+	root := parseFragment(t, `
+foo:
+  bar:
+    - zero
+    - one
+list:
+  - name: frontend
+    image: nginx
+  - name: backend
+    image: app
+`)
+
+	tests := []struct {
+		name string
+		path string
+		want []string
+	}{
+		{"mapping then index", "/foo/bar/0", []string{"zero"}},
+		{"key=value filter", "/list/name=frontend/image", []string{"nginx"}},
+		{"recursive descent", "**/image", []string{"nginx", "app"}},
+		{"no match", "/foo/missing", nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Get(root, tc.path)
+			if err != nil {
+				t.Fatalf("Get(%q): %v", tc.path, err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("Get(%q) = %d matches, want %d", tc.path, len(got), len(tc.want))
+			}
+			for i, n := range got {
+				if n.Value != tc.want[i] {
+					t.Errorf("Get(%q)[%d] = %q, want %q", tc.path, i, n.Value, tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetEmptySegmentError(t *testing.T) {
+	root := parseFragment(t, "foo: bar\n")
+	if _, err := Get(root, "/foo//bar"); err == nil {
+		t.Fatalf("expected an error for a path with an empty segment")
+	}
+}
+
+func TestSet(t *testing.T) {
+	root := parseFragment(t, `
+list:
+  - name: frontend
+    image: nginx
+  - name: backend
+    image: app
+`)
+
+	if err := Set(root, "/list/name=frontend/image", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "nginx:latest"}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	got, err := Get(root, "/list/name=frontend/image")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(got) != 1 || got[0].Value != "nginx:latest" {
+		t.Fatalf("Get after Set = %v, want [nginx:latest]", got)
+	}
+
+	other, err := Get(root, "/list/name=backend/image")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(other) != 1 || other[0].Value != "app" {
+		t.Fatalf("Set affected an unrelated node: %v", other)
+	}
+}
+
+func TestSetMultiMatchIndependence(t *testing.T) {
+	root := parseFragment(t, `
+list:
+  - name: frontend
+    image:
+      tag: old
+  - name: backend
+    image:
+      tag: old
+`)
+
+	replacement := &yaml.Node{
+		Kind: yaml.MappingNode,
+		Tag:  "!!map",
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "tag"},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "new"},
+		},
+	}
+	if err := Set(root, "**/image", replacement); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	matches, err := Get(root, "**/image")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("Get(**/image) = %d matches, want 2", len(matches))
+	}
+
+	// Mutating one match's child must not affect the other.
+	matches[0].Content[1].Value = "mutated"
+	if got := matches[1].Content[1].Value; got != "new" {
+		t.Fatalf("mutating matches[0] affected matches[1]: got %q, want %q", got, "new")
+	}
+}
+
+func TestSetNoMatch(t *testing.T) {
+	root := parseFragment(t, "foo: bar\n")
+	err := Set(root, "/missing", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "x"})
+	if err == nil {
+		t.Fatalf("expected an error when path matches nothing")
+	}
+}
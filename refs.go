@@ -0,0 +1,197 @@
+package yamltools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CycleRef records a !ref (or $ref) that was left unresolved because
+// resolving it would have formed a cycle.
+type CycleRef struct {
+	// Document is the file containing the referenced node, or "" if it is
+	// the document ResolveRefs was called with.
+	Document string
+	// Pointer is the JSON-pointer-like path within Document that the ref
+	// points to.
+	Pointer string
+}
+
+// refFrame identifies a ref currently being resolved, used to detect cycles.
+type refFrame struct {
+	document string
+	pointer  string
+}
+
+// ResolveRefs walks root looking for nodes tagged !ref (whose value is a
+// JSON-pointer-like path such as "/components/schemas/Pet" or
+// "file.yaml#/foo/bar") and mapping nodes containing a single "$ref" key,
+// and replaces each with the node it points to, loading external files
+// through LoadFileFragment.
+//
+// Resolution is lazy and idempotent: each ref is only resolved once, and
+// resolving again after a prior call converges immediately since a resolved
+// node no longer carries a !ref tag or $ref key. A ref that would form a
+// cycle through an ancestor still being resolved is left in place and
+// reported in the returned slice instead of causing an error.
+func ResolveRefs(root *yaml.Node) ([]CycleRef, error) {
+	r := &refResolver{root: root, documents: map[string]*yaml.Node{}}
+	err := r.walk(root, "")
+	return r.cycles, err
+}
+
+type refResolver struct {
+	root      *yaml.Node
+	documents map[string]*yaml.Node
+	stack     []refFrame
+	cycles    []CycleRef
+}
+
+func (r *refResolver) walk(n *yaml.Node, document string) error {
+	if n.Tag == "!ref" {
+		return r.resolveRef(n, n.Value, document)
+	}
+	if n.Kind == yaml.MappingNode {
+		if ref, ok := singleRefKey(n); ok {
+			return r.resolveRef(n, ref, document)
+		}
+		for i := 1; i < len(n.Content); i += 2 {
+			if err := r.walk(n.Content[i], document); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if n.Kind == yaml.SequenceNode {
+		for _, c := range n.Content {
+			if err := r.walk(c, document); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// singleRefKey reports whether n is a mapping containing exactly one key,
+// "$ref", and returns its value.
+func singleRefKey(n *yaml.Node) (string, bool) {
+	if len(n.Content) != 2 || n.Content[0].Value != "$ref" {
+		return "", false
+	}
+	return n.Content[1].Value, true
+}
+
+func (r *refResolver) resolveRef(n *yaml.Node, ref, fromDocument string) error {
+	targetDocument, targetPointer := splitRef(ref)
+	if targetDocument == "" {
+		targetDocument = fromDocument
+	}
+	frame := refFrame{targetDocument, targetPointer}
+	for _, f := range r.stack {
+		if f == frame {
+			r.cycles = append(r.cycles, CycleRef{Document: targetDocument, Pointer: targetPointer})
+			return nil
+		}
+	}
+
+	doc, err := r.loadDocument(targetDocument)
+	if err != nil {
+		return err
+	}
+	resolved, err := lookupPointer(doc, targetPointer)
+	if err != nil {
+		return err
+	}
+	// Clone before splicing in: resolved still points into doc (possibly a
+	// cached document shared by other ref sites), and the subsequent walk
+	// below mutates it further while resolving nested refs. Without the
+	// clone, every use of the same ref would alias the same nodes.
+	clone := cloneNode(resolved)
+
+	r.stack = append(r.stack, frame)
+	err = r.walk(clone, targetDocument)
+	r.stack = r.stack[:len(r.stack)-1]
+	if err != nil {
+		return err
+	}
+
+	*n = *clone
+	return nil
+}
+
+// cloneNode deep-copies n, including its Content slice and every
+// descendant node.
+func cloneNode(n *yaml.Node) *yaml.Node {
+	if n == nil {
+		return nil
+	}
+	clone := *n
+	if n.Content != nil {
+		clone.Content = make([]*yaml.Node, len(n.Content))
+		for i, c := range n.Content {
+			clone.Content[i] = cloneNode(c)
+		}
+	}
+	return &clone
+}
+
+func (r *refResolver) loadDocument(document string) (*yaml.Node, error) {
+	if document == "" {
+		return r.root, nil
+	}
+	if n, ok := r.documents[document]; ok {
+		return n, nil
+	}
+	n, err := LoadFileFragment(document)
+	if err != nil {
+		return nil, err
+	}
+	r.documents[document] = n
+	return n, nil
+}
+
+// splitRef splits a ref of the form "file.yaml#/foo/bar" into its document
+// and pointer parts. A ref with no "#" is treated as a pointer into the
+// current document.
+func splitRef(ref string) (document, pointer string) {
+	if i := strings.IndexByte(ref, '#'); i != -1 {
+		return ref[:i], ref[i+1:]
+	}
+	return "", ref
+}
+
+// lookupPointer resolves a JSON-pointer-like path against root.
+func lookupPointer(root *yaml.Node, pointer string) (*yaml.Node, error) {
+	n := root
+	pointer = strings.TrimPrefix(pointer, "/")
+	if pointer == "" {
+		return n, nil
+	}
+	for _, part := range strings.Split(pointer, "/") {
+		switch n.Kind {
+		case yaml.MappingNode:
+			found := false
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i].Value == part {
+					n = n.Content[i+1]
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("!ref: key %q not found", part)
+			}
+		case yaml.SequenceNode:
+			idx, err := strconv.Atoi(part)
+			if err != nil || idx < 0 || idx >= len(n.Content) {
+				return nil, fmt.Errorf("!ref: invalid sequence index %q", part)
+			}
+			n = n.Content[idx]
+		default:
+			return nil, fmt.Errorf("!ref: cannot index %q into a scalar", part)
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,96 @@
+package yamltools
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func parseFragment(t *testing.T, data string) *yaml.Node {
+	t.Helper()
+	var f Fragment
+	if err := yaml.Unmarshal([]byte(data), &f); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return f.Content
+}
+
+func findKey(t *testing.T, n *yaml.Node, key string) *yaml.Node {
+	t.Helper()
+	if n.Kind != yaml.MappingNode {
+		t.Fatalf("findKey(%q): node is not a mapping (kind=%v)", key, n.Kind)
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	t.Fatalf("findKey(%q): key not found", key)
+	return nil
+}
+
+func TestResolveRefs(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{
+			name: "!ref tag",
+			yaml: "defs:\n  widget: {name: button}\na: !ref /defs/widget\n",
+		},
+		{
+			name: "$ref mapping key",
+			yaml: "defs:\n  widget: {name: button}\na:\n  $ref: /defs/widget\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			root := parseFragment(t, tc.yaml)
+			cycles, err := ResolveRefs(root)
+			if err != nil {
+				t.Fatalf("ResolveRefs: %v", err)
+			}
+			if len(cycles) != 0 {
+				t.Fatalf("expected no cycles, got %v", cycles)
+			}
+			a := findKey(t, root, "a")
+			if a.Kind != yaml.MappingNode {
+				t.Fatalf("a: expected a mapping, got kind=%v", a.Kind)
+			}
+			if got := findKey(t, a, "name").Value; got != "button" {
+				t.Fatalf("a.name = %q, want %q", got, "button")
+			}
+		})
+	}
+}
+
+func TestResolveRefsCycle(t *testing.T) {
+	root := parseFragment(t, "a: !ref /b\nb: !ref /a\n")
+	cycles, err := ResolveRefs(root)
+	if err != nil {
+		t.Fatalf("ResolveRefs: %v", err)
+	}
+	if len(cycles) == 0 {
+		t.Fatalf("expected at least one cycle to be reported")
+	}
+}
+
+func TestResolveRefsIndependentCopies(t *testing.T) {
+	root := parseFragment(t, "defs:\n  widget: {name: button}\na: !ref /defs/widget\nb: !ref /defs/widget\n")
+	if _, err := ResolveRefs(root); err != nil {
+		t.Fatalf("ResolveRefs: %v", err)
+	}
+
+	a := findKey(t, root, "a")
+	b := findKey(t, root, "b")
+	findKey(t, a, "name").Value = "MUTATED"
+
+	if got := findKey(t, b, "name").Value; got != "button" {
+		t.Fatalf("mutating a.name affected b.name: got %q, want %q", got, "button")
+	}
+	defs := findKey(t, root, "defs")
+	widget := findKey(t, defs, "widget")
+	if got := findKey(t, widget, "name").Value; got != "button" {
+		t.Fatalf("mutating a.name affected the cached source document: got %q, want %q", got, "button")
+	}
+}
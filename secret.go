@@ -0,0 +1,25 @@
+package yamltools
+
+import "gopkg.in/yaml.v3"
+
+// SecretProvider resolves a named secret to its value, for use with
+// LoadSecretTag.
+type SecretProvider interface {
+	Get(name string) (string, error)
+}
+
+// LoadSecretTag returns a TagProcessor that recursively searches for the
+// !secret tag and replaces each occurrence with a scalar node holding the
+// value that provider returns for that name.
+func LoadSecretTag(provider SecretProvider) TagProcessor {
+	return func(n *yaml.Node) error {
+		return HandleCustomTag(n, "!secret", func(n *yaml.Node) error {
+			value, err := provider.Get(n.Value)
+			if err != nil {
+				return err
+			}
+			replaceWithString(n, value)
+			return nil
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package yamltools
+
+import (
+	"fmt"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type mapSecretProvider map[string]string
+
+func (p mapSecretProvider) Get(name string) (string, error) {
+	if v, ok := p[name]; ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("secret %q not found", name)
+}
+
+func TestLoadSecretTag(t *testing.T) {
+	provider := mapSecretProvider{"db_password": "hunter2"}
+	n := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!secret", Value: "db_password"}
+	if err := LoadSecretTag(provider)(n); err != nil {
+		t.Fatalf("LoadSecretTag: %v", err)
+	}
+	if n.Tag != "!!str" || n.Value != "hunter2" {
+		t.Fatalf("node = %+v, want a !!str scalar with value %q", n, "hunter2")
+	}
+}
+
+func TestLoadSecretTagProviderError(t *testing.T) {
+	provider := mapSecretProvider{}
+	n := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!secret", Value: "missing"}
+	if err := LoadSecretTag(provider)(n); err == nil {
+		t.Fatalf("expected an error for a secret the provider does not have")
+	}
+}